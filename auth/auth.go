@@ -0,0 +1,87 @@
+// Package auth implements JWT access token issuing and verification for
+// the go-users-api service.
+package auth
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Issuer is the value set on the Issuer claim of every access token minted
+// by this service.
+const Issuer = "go-users-api"
+
+// ErrInvalidToken is returned by ParseAccessToken when the token is
+// malformed, expired, or signed with an unexpected algorithm.
+var ErrInvalidToken = errors.New("auth: invalid or expired access token")
+
+// Claims are the JWT claims embedded in an access token. Subject holds the
+// user ID as a string, as required by the jwt.RegisteredClaims contract.
+type Claims struct {
+	jwt.RegisteredClaims
+}
+
+// UserID parses the Subject claim back into the numeric user ID it encodes.
+func (c Claims) UserID() (int, error) {
+	return parseUserID(c.Subject)
+}
+
+// GenerateAccessToken mints a signed JWT for the given user, valid until
+// expiresAt, audience "go-users-api-clients".
+func GenerateAccessToken(username string, userID int, expiresAt time.Time, secret []byte) (string, error) {
+	now := time.Now()
+
+	claims := Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   formatUserID(userID),
+			Issuer:    Issuer,
+			Audience:  jwt.ClaimStrings{"go-users-api-clients"},
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+
+	signed, err := token.SignedString(secret)
+	if err != nil {
+		return "", err
+	}
+
+	_ = username // reserved for future custom claims (e.g. display name)
+
+	return signed, nil
+}
+
+// ParseAccessToken validates the signature and standard claims of token and
+// returns the decoded Claims.
+func ParseAccessToken(token string, secret []byte) (Claims, error) {
+	var claims Claims
+
+	parsed, err := jwt.ParseWithClaims(token, &claims, func(t *jwt.Token) (any, error) {
+		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, ErrInvalidToken
+		}
+		return secret, nil
+	}, jwt.WithIssuer(Issuer))
+	if err != nil || !parsed.Valid {
+		return Claims{}, ErrInvalidToken
+	}
+
+	return claims, nil
+}
+
+// formatUserID encodes a numeric user ID as the string expected in the
+// Subject claim.
+func formatUserID(userID int) string {
+	return strconv.Itoa(userID)
+}
+
+// parseUserID decodes the Subject claim back into a numeric user ID.
+func parseUserID(subject string) (int, error) {
+	return strconv.Atoi(subject)
+}
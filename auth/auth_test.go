@@ -0,0 +1,52 @@
+package auth
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGenerateAndParseAccessToken_RoundTrip(t *testing.T) {
+	secret := []byte("test-secret")
+
+	token, err := GenerateAccessToken("alice", 42, time.Now().Add(time.Hour), secret)
+	if err != nil {
+		t.Fatalf("GenerateAccessToken() error = %v", err)
+	}
+
+	claims, err := ParseAccessToken(token, secret)
+	if err != nil {
+		t.Fatalf("ParseAccessToken() error = %v", err)
+	}
+
+	userID, err := claims.UserID()
+	if err != nil {
+		t.Fatalf("claims.UserID() error = %v", err)
+	}
+	if userID != 42 {
+		t.Fatalf("UserID() = %d, want 42", userID)
+	}
+}
+
+func TestParseAccessToken_RejectsExpired(t *testing.T) {
+	secret := []byte("test-secret")
+
+	token, err := GenerateAccessToken("alice", 42, time.Now().Add(-time.Minute), secret)
+	if err != nil {
+		t.Fatalf("GenerateAccessToken() error = %v", err)
+	}
+
+	if _, err := ParseAccessToken(token, secret); err != ErrInvalidToken {
+		t.Fatalf("ParseAccessToken() error = %v, want ErrInvalidToken", err)
+	}
+}
+
+func TestParseAccessToken_RejectsWrongSecret(t *testing.T) {
+	token, err := GenerateAccessToken("alice", 42, time.Now().Add(time.Hour), []byte("secret-a"))
+	if err != nil {
+		t.Fatalf("GenerateAccessToken() error = %v", err)
+	}
+
+	if _, err := ParseAccessToken(token, []byte("secret-b")); err != ErrInvalidToken {
+		t.Fatalf("ParseAccessToken() error = %v, want ErrInvalidToken", err)
+	}
+}
@@ -0,0 +1,114 @@
+package auth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/imariom/go-users-api/problem"
+)
+
+type fakeRevoker struct{ revoked map[string]bool }
+
+func (f fakeRevoker) IsRevoked(token string) bool { return f.revoked[token] }
+
+func TestMiddleware_InjectsUserID(t *testing.T) {
+	secret := []byte("test-secret")
+	token, err := GenerateAccessToken("alice", 7, time.Now().Add(time.Hour), secret)
+	if err != nil {
+		t.Fatalf("GenerateAccessToken() error = %v", err)
+	}
+
+	var gotUserID int
+	var gotOK bool
+	next := http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		gotUserID, gotOK = UserIDFromContext(r.Context())
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/users/7", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	rw := httptest.NewRecorder()
+
+	WithUserIDBox(Middleware(next, secret, fakeRevoker{})).ServeHTTP(rw, req)
+
+	if !gotOK || gotUserID != 7 {
+		t.Fatalf("UserIDFromContext() = (%d, %v), want (7, true)", gotUserID, gotOK)
+	}
+}
+
+// TestWithUserIDBox_VisibleToOuterMiddleware is a regression test: an outer
+// middleware that captured the request context *before* Middleware ran
+// (mirroring router.requestLogger, mounted outside the authenticated
+// /users sub-router) must still be able to read the user ID Middleware
+// sets deeper in the chain, since http.Request.WithContext would otherwise
+// make that update invisible to it.
+func TestWithUserIDBox_VisibleToOuterMiddleware(t *testing.T) {
+	secret := []byte("test-secret")
+	token, err := GenerateAccessToken("alice", 7, time.Now().Add(time.Hour), secret)
+	if err != nil {
+		t.Fatalf("GenerateAccessToken() error = %v", err)
+	}
+
+	var outerUserID int
+	var outerOK bool
+	outer := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(rw, r)
+			outerUserID, outerOK = UserIDFromContext(r.Context())
+		})
+	}
+
+	inner := Middleware(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {}), secret, fakeRevoker{})
+	chain := WithUserIDBox(outer(inner))
+
+	req := httptest.NewRequest(http.MethodGet, "/users/7", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	rw := httptest.NewRecorder()
+
+	chain.ServeHTTP(rw, req)
+
+	if !outerOK || outerUserID != 7 {
+		t.Fatalf("outer middleware's UserIDFromContext() = (%d, %v), want (7, true)", outerUserID, outerOK)
+	}
+}
+
+func TestMiddleware_RejectsRevokedToken(t *testing.T) {
+	secret := []byte("test-secret")
+	token, err := GenerateAccessToken("alice", 7, time.Now().Add(time.Hour), secret)
+	if err != nil {
+		t.Fatalf("GenerateAccessToken() error = %v", err)
+	}
+
+	next := http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		t.Fatal("next handler should not be called for a revoked token")
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/users/7", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	rw := httptest.NewRecorder()
+
+	Middleware(next, secret, fakeRevoker{revoked: map[string]bool{token: true}}).ServeHTTP(rw, req)
+
+	if rw.Code != http.StatusUnauthorized {
+		t.Fatalf("status = %d, want %d", rw.Code, http.StatusUnauthorized)
+	}
+}
+
+func TestMiddleware_RejectsMissingToken(t *testing.T) {
+	next := http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		t.Fatal("next handler should not be called without a token")
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/users/7", nil)
+	rw := httptest.NewRecorder()
+
+	Middleware(next, []byte("test-secret"), fakeRevoker{}).ServeHTTP(rw, req)
+
+	if rw.Code != http.StatusUnauthorized {
+		t.Fatalf("status = %d, want %d", rw.Code, http.StatusUnauthorized)
+	}
+	if ct := rw.Header().Get("Content-Type"); ct != problem.ContentType {
+		t.Fatalf("Content-Type = %q, want %q", ct, problem.ContentType)
+	}
+}
@@ -0,0 +1,520 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/imariom/go-users-api/auth"
+	"github.com/imariom/go-users-api/store"
+)
+
+// accessTokenTTL is how long a JWT minted by POST /login stays valid.
+const accessTokenTTL = 24 * time.Hour
+
+// patTTL is how long a personal access token minted by
+// POST /users/{id}/tokens stays valid.
+const patTTL = 365 * 24 * time.Hour
+
+// revokedTokens tracks JWTs revoked via POST /logout or
+// DELETE /users/{id}/tokens/{tokenID}. It is independent of the configured
+// store.UserStore backend, since revocation bookkeeping isn't part of user
+// persistence.
+type revokedTokens struct {
+	mu   sync.RWMutex
+	seen map[string]struct{}
+}
+
+func newRevokedTokens() *revokedTokens {
+	return &revokedTokens{seen: map[string]struct{}{}}
+}
+
+// IsRevoked implements auth.TokenRevoker.
+func (r *revokedTokens) IsRevoked(token string) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	_, ok := r.seen[token]
+	return ok
+}
+
+func (r *revokedTokens) revoke(token string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.seen[token] = struct{}{}
+}
+
+// UserHandler holds the HTTP handlers for the /users, /login and /logout
+// endpoints. Routing is handled by the router package; UserHandler only
+// implements the per-route logic.
+type UserHandler struct {
+	// users is the configured store.UserStore backend.
+	users store.UserStore
+
+	// Revoked tracks access tokens that must no longer be accepted. It is
+	// exported so the router package can wire it into auth.Middleware.
+	Revoked *revokedTokens
+
+	// secret signs and verifies the JWTs issued by this handler.
+	secret []byte
+
+	// maxListLimit caps the "?limit=" query parameter on GET /users.
+	maxListLimit int
+}
+
+// NewUserHandler returns a UserHandler backed by users.
+func NewUserHandler(users store.UserStore, secret []byte, maxListLimit int) *UserHandler {
+	return &UserHandler{
+		users:        users,
+		Revoked:      newRevokedTokens(),
+		secret:       secret,
+		maxListLimit: maxListLimit,
+	}
+}
+
+// Secret returns the JWT signing secret, so the router package can wire it
+// into auth.Middleware without reaching into an unexported field.
+func (h *UserHandler) Secret() []byte {
+	return h.secret
+}
+
+// idFromRequest parses the {id} chi URL parameter as a numeric user ID.
+func idFromRequest(r *http.Request) (int, error) {
+	return strconv.Atoi(chi.URLParam(r, "id"))
+}
+
+// requireOwner reports whether the user authenticated by auth.Middleware is
+// allowed to act on the user identified by id, writing a 403 problem
+// response and returning false otherwise. This API has no admin role yet,
+// so the only authorized caller for a given {id} is that same user.
+func requireOwner(rw http.ResponseWriter, r *http.Request, id int) bool {
+	authUserID, ok := auth.UserIDFromContext(r.Context())
+	if !ok || authUserID != id {
+		writeProblem(rw, r, http.StatusForbidden, "forbidden", "you may only act on your own user account")
+		return false
+	}
+	return true
+}
+
+// ListUsers handles GET /users, supporting pagination via "?limit=" and
+// "?offset="/"?cursor=", sorting via "?sort=", and exact/glob field filters
+// such as "?username=foo" or "?email=*@example.com".
+func (h *UserHandler) ListUsers(rw http.ResponseWriter, r *http.Request) {
+	opts, err := parseListOptions(r.URL.Query(), h.maxListLimit)
+	if err != nil {
+		writeProblem(rw, r, http.StatusBadRequest, "invalid query parameters", err.Error())
+		return
+	}
+
+	users, total, err := h.users.List(r.Context(), opts)
+	if err != nil {
+		writeProblem(rw, r, http.StatusInternalServerError, "failed to list users", err.Error())
+		return
+	}
+
+	if err := writeListResponse(rw, users, opts, total); err != nil {
+		writeProblem(rw, r, http.StatusInternalServerError, "failed to list users", err.Error())
+	}
+}
+
+// GetUser handles GET /users/{id}.
+func (h *UserHandler) GetUser(rw http.ResponseWriter, r *http.Request) {
+	id, err := idFromRequest(r)
+	if err != nil {
+		writeProblem(rw, r, http.StatusBadRequest, "invalid user id", err.Error())
+		return
+	}
+	if !requireOwner(rw, r, id) {
+		return
+	}
+
+	user, err := h.users.Get(id)
+	if err != nil {
+		writeStoreErr(rw, r, id, err)
+		return
+	}
+
+	if err := toJSON(user, rw); err != nil {
+		writeProblem(rw, r, http.StatusInternalServerError, "failed to get user", err.Error())
+	}
+}
+
+// CreateUser handles POST /users.
+func (h *UserHandler) CreateUser(rw http.ResponseWriter, r *http.Request) {
+	user := &store.User{}
+	if err := fromJSON(user, r.Body); err != nil {
+		writeProblem(rw, r, http.StatusBadRequest, "malformed user payload", err.Error())
+		return
+	}
+
+	if errs := validateUser(user); errs != nil {
+		writeProblem(rw, r, http.StatusBadRequest, "validation failed", "the user payload is invalid", errs...)
+		return
+	}
+
+	if err := hashPassword(user); err != nil {
+		writeProblem(rw, r, http.StatusInternalServerError, "failed to hash password", err.Error())
+		return
+	}
+
+	id, err := h.users.Create(user)
+	if err != nil {
+		writeStoreErr(rw, r, 0, err)
+		return
+	}
+	user.ID = id
+
+	rw.WriteHeader(http.StatusCreated)
+	if err := toJSON(user, rw); err != nil {
+		writeProblem(rw, r, http.StatusInternalServerError, "failed to retrieve created user", err.Error())
+	}
+}
+
+// UpdateUser handles PUT /users/{id}, wholesale-replacing the user record.
+func (h *UserHandler) UpdateUser(rw http.ResponseWriter, r *http.Request) {
+	id, err := idFromRequest(r)
+	if err != nil {
+		writeProblem(rw, r, http.StatusBadRequest, "invalid user id", err.Error())
+		return
+	}
+	if !requireOwner(rw, r, id) {
+		return
+	}
+
+	existing, err := h.users.Get(id)
+	if err != nil {
+		writeStoreErr(rw, r, id, err)
+		return
+	}
+
+	user := &store.User{}
+	if err := fromJSON(user, r.Body); err != nil {
+		writeProblem(rw, r, http.StatusBadRequest, "malformed user payload", err.Error())
+		return
+	}
+
+	if errs := validateUser(user); errs != nil {
+		writeProblem(rw, r, http.StatusBadRequest, "validation failed", "the user payload is invalid", errs...)
+		return
+	}
+
+	if err := hashPassword(user); err != nil {
+		writeProblem(rw, r, http.StatusInternalServerError, "failed to hash password", err.Error())
+		return
+	}
+	// The request body has no way to carry Tokens (json:"-"), so carry
+	// over the existing ones ourselves; otherwise Update would replace
+	// them with nil and silently revoke every PAT this user has minted.
+	user.Tokens = existing.Tokens
+
+	if err := h.users.Update(id, user); err != nil {
+		writeStoreErr(rw, r, id, err)
+		return
+	}
+	user.ID = id
+
+	if err := toJSON(user, rw); err != nil {
+		writeProblem(rw, r, http.StatusInternalServerError, "failed to get user", err.Error())
+	}
+}
+
+// patchUserRequest is the JSON merge patch payload for PATCH /users/{id}.
+// Only fields present in the request body are applied; omitted fields are
+// left untouched on the stored user.
+type patchUserRequest struct {
+	Username *string `json:"username"`
+	Password *string `json:"password"`
+	Email    *string `json:"email"`
+}
+
+// PatchUser handles PATCH /users/{id}, applying a partial update where only
+// the fields present in the request body are modified.
+func (h *UserHandler) PatchUser(rw http.ResponseWriter, r *http.Request) {
+	id, err := idFromRequest(r)
+	if err != nil {
+		writeProblem(rw, r, http.StatusBadRequest, "invalid user id", err.Error())
+		return
+	}
+	if !requireOwner(rw, r, id) {
+		return
+	}
+
+	patch := &patchUserRequest{}
+	if err := json.NewDecoder(r.Body).Decode(patch); err != nil {
+		writeProblem(rw, r, http.StatusBadRequest, "malformed user payload", err.Error())
+		return
+	}
+
+	user, err := h.users.Get(id)
+	if err != nil {
+		writeStoreErr(rw, r, id, err)
+		return
+	}
+
+	if patch.Username != nil {
+		user.Username = *patch.Username
+	}
+	if patch.Email != nil {
+		user.Email = *patch.Email
+	}
+	if patch.Password != nil {
+		user.Password = *patch.Password
+	}
+
+	if errs := validateUser(user); errs != nil {
+		writeProblem(rw, r, http.StatusBadRequest, "validation failed", "the user payload is invalid", errs...)
+		return
+	}
+
+	if patch.Password != nil {
+		if err := hashPassword(user); err != nil {
+			writeProblem(rw, r, http.StatusInternalServerError, "failed to hash password", err.Error())
+			return
+		}
+	}
+
+	if err := h.users.Update(id, user); err != nil {
+		writeStoreErr(rw, r, id, err)
+		return
+	}
+
+	if err := toJSON(user, rw); err != nil {
+		writeProblem(rw, r, http.StatusInternalServerError, "failed to get user", err.Error())
+	}
+}
+
+// DeleteUser handles DELETE /users/{id}.
+func (h *UserHandler) DeleteUser(rw http.ResponseWriter, r *http.Request) {
+	id, err := idFromRequest(r)
+	if err != nil {
+		writeProblem(rw, r, http.StatusBadRequest, "invalid user id", err.Error())
+		return
+	}
+	if !requireOwner(rw, r, id) {
+		return
+	}
+
+	user, err := h.users.Delete(id)
+	if err != nil {
+		writeStoreErr(rw, r, id, err)
+		return
+	}
+
+	if err := toJSON(user, rw); err != nil {
+		writeProblem(rw, r, http.StatusInternalServerError, "failed to get deleted user", err.Error())
+	}
+}
+
+// writeStoreErr translates a store.UserStore error into the matching
+// problem+json response for the given user id.
+func writeStoreErr(rw http.ResponseWriter, r *http.Request, id int, err error) {
+	switch {
+	case errors.Is(err, store.ErrNotFound):
+		writeProblem(rw, r, http.StatusNotFound, "user not found", fmt.Sprintf("user %d not found", id))
+
+	case errors.Is(err, store.ErrConflict):
+		writeProblem(rw, r, http.StatusConflict, "username or email already in use", err.Error())
+
+	default:
+		writeProblem(rw, r, http.StatusInternalServerError, "store operation failed", err.Error())
+	}
+}
+
+// loginRequest is the expected payload for POST /login.
+type loginRequest struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+// Login handles POST /login: it authenticates a user by username and
+// password and returns a signed JWT access token, also set as the
+// access-token cookie.
+func (h *UserHandler) Login(rw http.ResponseWriter, r *http.Request) {
+	creds := &loginRequest{}
+	if err := json.NewDecoder(r.Body).Decode(creds); err != nil {
+		writeProblem(rw, r, http.StatusBadRequest, "malformed login payload", err.Error())
+		return
+	}
+
+	user, err := h.users.GetByUsername(creds.Username)
+	if err != nil || !checkPassword(user, creds.Password) {
+		writeProblem(rw, r, http.StatusUnauthorized, "invalid credentials", "invalid username or password")
+		return
+	}
+
+	token, err := auth.GenerateAccessToken(user.Username, user.ID, time.Now().Add(accessTokenTTL), h.secret)
+	if err != nil {
+		writeProblem(rw, r, http.StatusInternalServerError, "failed to generate access token", err.Error())
+		return
+	}
+
+	http.SetCookie(rw, &http.Cookie{
+		Name:     "access-token",
+		Value:    token,
+		Expires:  time.Now().Add(accessTokenTTL),
+		HttpOnly: true,
+		Path:     "/",
+	})
+
+	json.NewEncoder(rw).Encode(map[string]string{"access_token": token})
+}
+
+// Logout handles POST /logout: it revokes the access token used to
+// authenticate the request so it can no longer be used.
+func (h *UserHandler) Logout(rw http.ResponseWriter, r *http.Request) {
+	token, ok := extractRequestToken(r)
+	if !ok {
+		writeProblem(rw, r, http.StatusBadRequest, "missing access token", "no access token found on the request")
+		return
+	}
+
+	h.Revoked.revoke(token)
+
+	http.SetCookie(rw, &http.Cookie{
+		Name:     "access-token",
+		Value:    "",
+		Expires:  time.Unix(0, 0),
+		HttpOnly: true,
+		Path:     "/",
+	})
+
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+// extractRequestToken mirrors auth.Middleware's token lookup so /logout can
+// find the token to revoke without itself requiring the middleware.
+func extractRequestToken(r *http.Request) (string, bool) {
+	if h := r.Header.Get("Authorization"); len(h) > len("Bearer ") && h[:len("Bearer ")] == "Bearer " {
+		return h[len("Bearer "):], true
+	}
+
+	if cookie, err := r.Cookie("access-token"); err == nil && cookie.Value != "" {
+		return cookie.Value, true
+	}
+
+	return "", false
+}
+
+// createTokenRequest is the expected payload for POST /users/{id}/tokens.
+type createTokenRequest struct {
+	Name string `json:"name"`
+}
+
+// CreateToken handles POST /users/{id}/tokens: it mints a long-lived
+// personal access token for the user identified by {id} and stores it
+// alongside the user record.
+func (h *UserHandler) CreateToken(rw http.ResponseWriter, r *http.Request) {
+	id, err := idFromRequest(r)
+	if err != nil {
+		writeProblem(rw, r, http.StatusBadRequest, "invalid user id", err.Error())
+		return
+	}
+	if !requireOwner(rw, r, id) {
+		return
+	}
+
+	req := &createTokenRequest{}
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		writeProblem(rw, r, http.StatusBadRequest, "malformed token payload", err.Error())
+		return
+	}
+
+	user, err := h.users.Get(id)
+	if err != nil {
+		writeStoreErr(rw, r, id, err)
+		return
+	}
+
+	tokenID, err := randomTokenID()
+	if err != nil {
+		writeProblem(rw, r, http.StatusInternalServerError, "failed to generate token id", err.Error())
+		return
+	}
+
+	expiresAt := time.Now().Add(patTTL)
+
+	signed, err := auth.GenerateAccessToken(user.Username, user.ID, expiresAt, h.secret)
+	if err != nil {
+		writeProblem(rw, r, http.StatusInternalServerError, "failed to generate access token", err.Error())
+		return
+	}
+
+	pat := store.PersonalAccessToken{
+		ID:        tokenID,
+		Name:      req.Name,
+		Token:     signed,
+		CreatedAt: time.Now(),
+		ExpiresAt: expiresAt,
+	}
+	user.Tokens = append(user.Tokens, pat)
+
+	if err := h.users.Update(id, user); err != nil {
+		writeStoreErr(rw, r, id, err)
+		return
+	}
+
+	if err := json.NewEncoder(rw).Encode(pat); err != nil {
+		writeProblem(rw, r, http.StatusInternalServerError, "failed to retrieve created token", err.Error())
+	}
+}
+
+// DeleteToken handles DELETE /users/{id}/tokens/{tokenID}: it revokes the
+// personal access token {tokenID} belonging to user {id}.
+func (h *UserHandler) DeleteToken(rw http.ResponseWriter, r *http.Request) {
+	id, err := idFromRequest(r)
+	if err != nil {
+		writeProblem(rw, r, http.StatusBadRequest, "invalid user id", err.Error())
+		return
+	}
+	if !requireOwner(rw, r, id) {
+		return
+	}
+	tokenID := chi.URLParam(r, "tokenID")
+
+	user, err := h.users.Get(id)
+	if err != nil {
+		writeStoreErr(rw, r, id, err)
+		return
+	}
+
+	idx := -1
+	for i, t := range user.Tokens {
+		if t.ID == tokenID {
+			idx = i
+			break
+		}
+	}
+	if idx == -1 {
+		writeProblem(rw, r, http.StatusNotFound, "token not found", fmt.Sprintf("token %s not found", tokenID))
+		return
+	}
+
+	h.Revoked.revoke(user.Tokens[idx].Token)
+	user.Tokens = append(user.Tokens[:idx], user.Tokens[idx+1:]...)
+
+	if err := h.users.Update(id, user); err != nil {
+		writeStoreErr(rw, r, id, err)
+		return
+	}
+
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+// randomTokenID generates a short random identifier for a personal access
+// token.
+func randomTokenID() (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,202 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/imariom/go-users-api/router"
+	"github.com/imariom/go-users-api/store"
+	"github.com/imariom/go-users-api/store/memstore"
+)
+
+// testServer wires a UserHandler backed by a fresh memstore onto a real
+// router, so tests exercise auth.Middleware exactly as it runs in
+// production rather than stubbing context values by hand.
+func testServer(t *testing.T) (http.Handler, *UserHandler) {
+	t.Helper()
+
+	h := NewUserHandler(memstore.New(), []byte("test-secret"), 100)
+	return router.New(h, h.Revoked, nil), h
+}
+
+// createTestUser creates a user directly on the store, bypassing
+// POST /users, and returns its ID.
+func createTestUser(t *testing.T, h *UserHandler, username, email, password string) int {
+	t.Helper()
+
+	u := &store.User{Username: username, Password: password, Email: email}
+	if err := hashPassword(u); err != nil {
+		t.Fatalf("hashPassword() error = %v", err)
+	}
+
+	id, err := h.users.Create(u)
+	if err != nil {
+		t.Fatalf("users.Create() error = %v", err)
+	}
+	return id
+}
+
+// loginToken logs in as username/password and returns the issued access
+// token.
+func loginToken(t *testing.T, mux http.Handler, username, password string) string {
+	t.Helper()
+
+	body, _ := json.Marshal(loginRequest{Username: username, Password: password})
+	req := httptest.NewRequest(http.MethodPost, "/login", bytes.NewReader(body))
+	rw := httptest.NewRecorder()
+	mux.ServeHTTP(rw, req)
+
+	if rw.Code != http.StatusOK {
+		t.Fatalf("POST /login status = %d, body = %s", rw.Code, rw.Body.String())
+	}
+
+	var resp map[string]string
+	if err := json.Unmarshal(rw.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decoding /login response: %v", err)
+	}
+	return resp["access_token"]
+}
+
+func authedRequest(method, path, token string, body []byte) *http.Request {
+	var r *http.Request
+	if body != nil {
+		r = httptest.NewRequest(method, path, bytes.NewReader(body))
+	} else {
+		r = httptest.NewRequest(method, path, nil)
+	}
+	r.Header.Set("Authorization", "Bearer "+token)
+	return r
+}
+
+func TestOwnership_CannotActOnAnotherUsersRecord(t *testing.T) {
+	mux, h := testServer(t)
+
+	aliceID := createTestUser(t, h, "alice", "alice@example.com", "hunter22")
+	bobID := createTestUser(t, h, "bob", "bob@example.com", "hunter22")
+
+	aliceToken := loginToken(t, mux, "alice", "hunter22")
+
+	cases := []struct {
+		name   string
+		method string
+		path   string
+	}{
+		{"GetUser", http.MethodGet, "/users/%d"},
+		{"DeleteUser", http.MethodDelete, "/users/%d"},
+		{"CreateToken", http.MethodPost, "/users/%d/tokens"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			path := fmt.Sprintf(tc.path, bobID)
+
+			var body []byte
+			if tc.method == http.MethodPost {
+				body, _ = json.Marshal(createTokenRequest{Name: "ci"})
+			}
+
+			req := authedRequest(tc.method, path, aliceToken, body)
+			rw := httptest.NewRecorder()
+			mux.ServeHTTP(rw, req)
+
+			if rw.Code != http.StatusForbidden {
+				t.Fatalf("%s %s as alice against bob's id: status = %d, want %d", tc.method, path, rw.Code, http.StatusForbidden)
+			}
+		})
+	}
+
+	// Acting on her own record still works.
+	req := authedRequest(http.MethodGet, fmt.Sprintf("/users/%d", aliceID), aliceToken, nil)
+	rw := httptest.NewRecorder()
+	mux.ServeHTTP(rw, req)
+	if rw.Code != http.StatusOK {
+		t.Fatalf("GET /users/{own id}: status = %d, body = %s", rw.Code, rw.Body.String())
+	}
+}
+
+func TestPatchUser_OnlyAppliesPresentFields(t *testing.T) {
+	mux, h := testServer(t)
+
+	aliceID := createTestUser(t, h, "alice", "alice@example.com", "hunter22")
+	aliceToken := loginToken(t, mux, "alice", "hunter22")
+
+	body, _ := json.Marshal(patchUserRequest{Email: strPtr("alice2@example.com")})
+	req := authedRequest(http.MethodPatch, fmt.Sprintf("/users/%d", aliceID), aliceToken, body)
+	rw := httptest.NewRecorder()
+	mux.ServeHTTP(rw, req)
+	if rw.Code != http.StatusOK {
+		t.Fatalf("PATCH /users/{id}: status = %d, body = %s", rw.Code, rw.Body.String())
+	}
+
+	got, err := h.users.Get(aliceID)
+	if err != nil {
+		t.Fatalf("users.Get() error = %v", err)
+	}
+	if got.Username != "alice" {
+		t.Fatalf("Username = %q, want unchanged %q", got.Username, "alice")
+	}
+	if got.Email != "alice2@example.com" {
+		t.Fatalf("Email = %q, want %q", got.Email, "alice2@example.com")
+	}
+}
+
+// TestLogin_UsernameGlobDoesNotMatchEveryUser is a regression test: Login
+// used to look up credentials with the same store.List call GET /users
+// uses for its username filter, where "*" matches every user. That let a
+// request log in as an arbitrary account without knowing its username, as
+// long as the attacker could guess any user's password.
+func TestLogin_UsernameGlobDoesNotMatchEveryUser(t *testing.T) {
+	mux, h := testServer(t)
+
+	createTestUser(t, h, "alice", "alice@example.com", "hunter22")
+
+	body, _ := json.Marshal(loginRequest{Username: "*", Password: "hunter22"})
+	req := httptest.NewRequest(http.MethodPost, "/login", bytes.NewReader(body))
+	rw := httptest.NewRecorder()
+	mux.ServeHTTP(rw, req)
+
+	if rw.Code != http.StatusUnauthorized {
+		t.Fatalf(`POST /login with username="*": status = %d, want %d`, rw.Code, http.StatusUnauthorized)
+	}
+}
+
+// TestUpdateUser_PreservesExistingTokens is a regression test: UpdateUser
+// decoded the request body straight into a fresh store.User, whose Tokens
+// field is always nil (it's json:"-"), so an unrelated PUT profile edit
+// silently wiped out every personal access token the user had minted.
+func TestUpdateUser_PreservesExistingTokens(t *testing.T) {
+	mux, h := testServer(t)
+
+	aliceID := createTestUser(t, h, "alice", "alice@example.com", "hunter22")
+	aliceToken := loginToken(t, mux, "alice", "hunter22")
+
+	tokenBody, _ := json.Marshal(createTokenRequest{Name: "ci"})
+	req := authedRequest(http.MethodPost, fmt.Sprintf("/users/%d/tokens", aliceID), aliceToken, tokenBody)
+	rw := httptest.NewRecorder()
+	mux.ServeHTTP(rw, req)
+	if rw.Code != http.StatusOK {
+		t.Fatalf("POST /users/{id}/tokens: status = %d, body = %s", rw.Code, rw.Body.String())
+	}
+
+	updateBody, _ := json.Marshal(store.User{Username: "alice", Email: "alice2@example.com", Password: "hunter22"})
+	req = authedRequest(http.MethodPut, fmt.Sprintf("/users/%d", aliceID), aliceToken, updateBody)
+	rw = httptest.NewRecorder()
+	mux.ServeHTTP(rw, req)
+	if rw.Code != http.StatusOK {
+		t.Fatalf("PUT /users/{id}: status = %d, body = %s", rw.Code, rw.Body.String())
+	}
+
+	got, err := h.users.Get(aliceID)
+	if err != nil {
+		t.Fatalf("users.Get() error = %v", err)
+	}
+	if len(got.Tokens) != 1 {
+		t.Fatalf("Tokens after PUT = %+v, want the 1 token minted before the update", got.Tokens)
+	}
+}
+
+func strPtr(s string) *string { return &s }
@@ -0,0 +1,46 @@
+// Package logging configures the process-wide structured logger used
+// throughout go-users-api.
+package logging
+
+import (
+	"context"
+	"log/slog"
+	"os"
+
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+
+	"github.com/imariom/go-users-api/auth"
+)
+
+// contextHandler wraps a slog.Handler, enriching every record with the
+// chi request ID and authenticated user ID found in ctx (when present) so
+// call sites don't have to attach them by hand on every log call.
+type contextHandler struct {
+	slog.Handler
+}
+
+// Handle implements slog.Handler.
+func (h contextHandler) Handle(ctx context.Context, r slog.Record) error {
+	if reqID := chimiddleware.GetReqID(ctx); reqID != "" {
+		r.AddAttrs(slog.String("request_id", reqID))
+	}
+	if userID, ok := auth.UserIDFromContext(ctx); ok {
+		r.AddAttrs(slog.Int("user_id", userID))
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+// New returns the logger go-users-api should install as its default. In
+// "production" it emits JSON; anywhere else it emits human-readable text.
+func New(env string) *slog.Logger {
+	opts := &slog.HandlerOptions{}
+
+	var base slog.Handler
+	if env == "production" {
+		base = slog.NewJSONHandler(os.Stdout, opts)
+	} else {
+		base = slog.NewTextHandler(os.Stdout, opts)
+	}
+
+	return slog.New(contextHandler{base})
+}
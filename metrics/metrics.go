@@ -0,0 +1,78 @@
+// Package metrics registers the Prometheus collectors exposed by
+// go-users-api on /metrics and the middleware that keeps them updated.
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/imariom/go-users-api/store"
+)
+
+// HTTPRequestsTotal counts completed HTTP requests by method, route
+// pattern and status code.
+var HTTPRequestsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "http_requests_total",
+	Help: "Total number of HTTP requests processed.",
+}, []string{"method", "path", "status"})
+
+// HTTPRequestDuration observes request latency by method and route pattern.
+var HTTPRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+	Name:    "http_request_duration_seconds",
+	Help:    "HTTP request latency in seconds.",
+	Buckets: prometheus.DefBuckets,
+}, []string{"method", "path"})
+
+func init() {
+	prometheus.MustRegister(HTTPRequestsTotal, HTTPRequestDuration)
+}
+
+// Middleware instruments every request it wraps, recording
+// HTTPRequestsTotal and HTTPRequestDuration. Mount it once at the top of
+// the router so instrumentation is uniform across all endpoints.
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+
+		ww := chimiddleware.NewWrapResponseWriter(rw, r.ProtoMajor)
+		next.ServeHTTP(ww, r)
+
+		path := RoutePattern(r)
+		HTTPRequestsTotal.WithLabelValues(r.Method, path, strconv.Itoa(ww.Status())).Inc()
+		HTTPRequestDuration.WithLabelValues(r.Method, path).Observe(time.Since(start).Seconds())
+	})
+}
+
+// RoutePattern returns the chi route pattern matched for r (e.g.
+// "/users/{id}"), falling back to the raw path if chi hasn't matched yet.
+// Exported so router's request logger can report the same pattern this
+// package's metrics are keyed on instead of keeping a second copy.
+func RoutePattern(r *http.Request) string {
+	if rctx := chi.RouteContext(r.Context()); rctx != nil {
+		if pattern := rctx.RoutePattern(); pattern != "" {
+			return pattern
+		}
+	}
+	return r.URL.Path
+}
+
+// RegisterUsersTotal registers a gauge that reports the current number of
+// users known to users on every scrape.
+func RegisterUsersTotal(users store.UserStore) {
+	prometheus.MustRegister(prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Name: "users_total",
+		Help: "Current number of users in the configured store.",
+	}, func() float64 {
+		_, total, err := users.List(context.Background(), store.ListOptions{})
+		if err != nil {
+			return 0
+		}
+		return float64(total)
+	}))
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/imariom/go-users-api/store"
+)
+
+// allowedSortFields are the only values accepted in "?sort=".
+var allowedSortFields = map[string]bool{
+	"id": true, "-id": true,
+	"username": true, "-username": true,
+}
+
+// listEnvelope is the response body for GET /users.
+type listEnvelope struct {
+	Data       []*publicUser  `json:"data"`
+	Pagination paginationInfo `json:"pagination"`
+}
+
+// paginationInfo describes the page of results returned relative to the
+// full matching set.
+type paginationInfo struct {
+	Total      int    `json:"total"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// parseListOptions builds a store.ListOptions from the GET /users query
+// string, validating "sort" and capping "limit" at maxLimit. "offset" and
+// "cursor" are equivalent ways of specifying where the page starts;
+// "cursor" takes precedence if both are given.
+func parseListOptions(query url.Values, maxLimit int) (store.ListOptions, error) {
+	opts := store.ListOptions{
+		Username: query.Get("username"),
+		Email:    query.Get("email"),
+	}
+
+	if sort := query.Get("sort"); sort != "" {
+		if !allowedSortFields[sort] {
+			return opts, fmt.Errorf("unknown sort field %q", sort)
+		}
+		opts.Sort = sort
+	}
+
+	opts.Limit = maxLimit
+	if limitParam := query.Get("limit"); limitParam != "" {
+		limit, err := strconv.Atoi(limitParam)
+		if err != nil || limit < 0 {
+			return opts, fmt.Errorf("invalid limit %q", limitParam)
+		}
+		opts.Limit = limit
+	}
+	if opts.Limit <= 0 || opts.Limit > maxLimit {
+		opts.Limit = maxLimit
+	}
+
+	if cursor := query.Get("cursor"); cursor != "" {
+		offset, err := decodeCursor(cursor)
+		if err != nil {
+			return opts, fmt.Errorf("invalid cursor %q", cursor)
+		}
+		opts.Offset = offset
+	} else if offsetParam := query.Get("offset"); offsetParam != "" {
+		offset, err := strconv.Atoi(offsetParam)
+		if err != nil || offset < 0 {
+			return opts, fmt.Errorf("invalid offset %q", offsetParam)
+		}
+		opts.Offset = offset
+	}
+
+	return opts, nil
+}
+
+// encodeCursor turns an offset into the opaque string returned as
+// pagination.next_cursor.
+func encodeCursor(offset int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.Itoa(offset)))
+}
+
+// decodeCursor reverses encodeCursor.
+func decodeCursor(cursor string) (int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(string(b))
+}
+
+// writeListResponse encodes users (already the requested page) alongside a
+// pagination envelope describing total matches and, if more remain, a
+// cursor for the next page.
+func writeListResponse(rw http.ResponseWriter, users []*store.User, opts store.ListOptions, total int) error {
+	publicUsers := make([]*publicUser, 0, len(users))
+	for _, u := range users {
+		publicUsers = append(publicUsers, public(u))
+	}
+
+	env := listEnvelope{
+		Data:       publicUsers,
+		Pagination: paginationInfo{Total: total},
+	}
+
+	if nextOffset := opts.Offset + len(users); nextOffset < total {
+		env.Pagination.NextCursor = encodeCursor(nextOffset)
+	}
+
+	return json.NewEncoder(rw).Encode(env)
+}
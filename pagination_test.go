@@ -0,0 +1,72 @@
+package main
+
+import (
+	"net/url"
+	"testing"
+)
+
+func TestParseListOptions_LimitClampedToMax(t *testing.T) {
+	opts, err := parseListOptions(url.Values{"limit": {"500"}}, 100)
+	if err != nil {
+		t.Fatalf("parseListOptions() error = %v", err)
+	}
+	if opts.Limit != 100 {
+		t.Fatalf("Limit = %d, want 100 (clamped to maxLimit)", opts.Limit)
+	}
+}
+
+func TestParseListOptions_DefaultsToMaxLimit(t *testing.T) {
+	opts, err := parseListOptions(url.Values{}, 100)
+	if err != nil {
+		t.Fatalf("parseListOptions() error = %v", err)
+	}
+	if opts.Limit != 100 {
+		t.Fatalf("Limit = %d, want 100 (default)", opts.Limit)
+	}
+}
+
+func TestParseListOptions_RejectsUnknownSortField(t *testing.T) {
+	if _, err := parseListOptions(url.Values{"sort": {"password"}}, 100); err == nil {
+		t.Fatal("parseListOptions() error = nil, want error for unknown sort field")
+	}
+}
+
+func TestParseListOptions_RejectsInvalidLimit(t *testing.T) {
+	if _, err := parseListOptions(url.Values{"limit": {"not-a-number"}}, 100); err == nil {
+		t.Fatal("parseListOptions() error = nil, want error for non-numeric limit")
+	}
+	if _, err := parseListOptions(url.Values{"limit": {"-1"}}, 100); err == nil {
+		t.Fatal("parseListOptions() error = nil, want error for negative limit")
+	}
+}
+
+func TestParseListOptions_CursorTakesPrecedenceOverOffset(t *testing.T) {
+	opts, err := parseListOptions(url.Values{
+		"cursor": {encodeCursor(10)},
+		"offset": {"999"},
+	}, 100)
+	if err != nil {
+		t.Fatalf("parseListOptions() error = %v", err)
+	}
+	if opts.Offset != 10 {
+		t.Fatalf("Offset = %d, want 10 (from cursor, not offset)", opts.Offset)
+	}
+}
+
+func TestParseListOptions_RejectsMalformedCursor(t *testing.T) {
+	if _, err := parseListOptions(url.Values{"cursor": {"not-base64!!"}}, 100); err == nil {
+		t.Fatal("parseListOptions() error = nil, want error for malformed cursor")
+	}
+}
+
+func TestCursor_RoundTrip(t *testing.T) {
+	cursor := encodeCursor(42)
+
+	offset, err := decodeCursor(cursor)
+	if err != nil {
+		t.Fatalf("decodeCursor() error = %v", err)
+	}
+	if offset != 42 {
+		t.Fatalf("decodeCursor() = %d, want 42", offset)
+	}
+}
@@ -0,0 +1,27 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/imariom/go-users-api/problem"
+)
+
+// problemContentType is the media type RFC 7807 reserves for problem
+// details responses.
+const problemContentType = problem.ContentType
+
+// fieldError describes one field-level validation failure. It populates
+// the "errors" array of a 400 problem response.
+type fieldError = problem.FieldError
+
+// problemDetails is an RFC 7807 "Problem Details for HTTP APIs" response
+// body. Errors is only populated for validation failures.
+type problemDetails = problem.Details
+
+// writeProblem writes an RFC 7807 application/problem+json response and
+// logs it, replacing the plain-text http.Error calls UserHandler used to
+// make despite advertising a JSON API. It delegates to package problem so
+// auth.Middleware and router's middleware can emit the same response shape.
+func writeProblem(rw http.ResponseWriter, r *http.Request, status int, title, detail string, errs ...fieldError) {
+	problem.Write(rw, r, status, title, detail, errs...)
+}
@@ -0,0 +1,53 @@
+// Package problem implements RFC 7807 "Problem Details for HTTP APIs"
+// responses, so every package that can reject a request at the edge —
+// main's handlers, auth's middleware, router's middleware — shares one
+// response shape instead of each inventing its own error format.
+package problem
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+)
+
+// ContentType is the media type RFC 7807 reserves for problem details
+// responses.
+const ContentType = "application/problem+json"
+
+// FieldError describes one field-level validation failure. It populates
+// the "errors" array of a 400 problem response.
+type FieldError struct {
+	Field  string `json:"field"`
+	Reason string `json:"reason"`
+}
+
+// Details is an RFC 7807 "Problem Details for HTTP APIs" response body.
+// Errors is only populated for validation failures.
+type Details struct {
+	Type   string       `json:"type"`
+	Title  string       `json:"title"`
+	Status int          `json:"status"`
+	Detail string       `json:"detail,omitempty"`
+	Errors []FieldError `json:"errors,omitempty"`
+}
+
+// Write writes an RFC 7807 application/problem+json response and logs it,
+// replacing the plain-text http.Error calls this API used to make despite
+// advertising a JSON contract.
+func Write(rw http.ResponseWriter, r *http.Request, status int, title, detail string, errs ...FieldError) {
+	if status >= http.StatusInternalServerError {
+		slog.ErrorContext(r.Context(), title, "detail", detail)
+	} else {
+		slog.InfoContext(r.Context(), title, "detail", detail)
+	}
+
+	rw.Header().Set("Content-Type", ContentType)
+	rw.WriteHeader(status)
+	json.NewEncoder(rw).Encode(Details{
+		Type:   "about:blank",
+		Title:  title,
+		Status: status,
+		Detail: detail,
+		Errors: errs,
+	})
+}
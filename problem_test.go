@@ -0,0 +1,46 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestWriteProblem_SetsContentTypeAndBody(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/users/999", nil)
+	rw := httptest.NewRecorder()
+
+	writeProblem(rw, req, http.StatusNotFound, "user not found", "user 999 not found")
+
+	if ct := rw.Header().Get("Content-Type"); ct != problemContentType {
+		t.Fatalf("Content-Type = %q, want %q", ct, problemContentType)
+	}
+	if rw.Code != http.StatusNotFound {
+		t.Fatalf("status = %d, want %d", rw.Code, http.StatusNotFound)
+	}
+
+	var got problemDetails
+	if err := json.Unmarshal(rw.Body.Bytes(), &got); err != nil {
+		t.Fatalf("decoding response body: %v", err)
+	}
+	if got.Status != http.StatusNotFound || got.Title != "user not found" {
+		t.Fatalf("problemDetails = %+v, want Status=404 Title=%q", got, "user not found")
+	}
+}
+
+func TestWriteProblem_IncludesFieldErrors(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/users", nil)
+	rw := httptest.NewRecorder()
+
+	writeProblem(rw, req, http.StatusBadRequest, "validation failed", "the user payload is invalid",
+		fieldError{Field: "email", Reason: "must be a valid email address"})
+
+	var got problemDetails
+	if err := json.Unmarshal(rw.Body.Bytes(), &got); err != nil {
+		t.Fatalf("decoding response body: %v", err)
+	}
+	if len(got.Errors) != 1 || got.Errors[0].Field != "email" {
+		t.Fatalf("problemDetails.Errors = %+v, want one error for field email", got.Errors)
+	}
+}
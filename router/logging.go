@@ -0,0 +1,31 @@
+package router
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+
+	"github.com/imariom/go-users-api/metrics"
+)
+
+// requestLogger emits one structured log line per request, replacing the
+// per-handler log.Println calls UserHandler used to make. method, path and
+// status are recorded here; request_id and (once authenticated) user_id
+// are added automatically by logging.New's handler.
+func requestLogger(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+
+		ww := chimiddleware.NewWrapResponseWriter(rw, r.ProtoMajor)
+		next.ServeHTTP(ww, r)
+
+		slog.InfoContext(r.Context(), "request handled",
+			"method", r.Method,
+			"path", metrics.RoutePattern(r),
+			"status", ww.Status(),
+			"duration_ms", time.Since(start).Milliseconds(),
+		)
+	})
+}
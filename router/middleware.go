@@ -0,0 +1,24 @@
+package router
+
+import (
+	"net/http"
+
+	"github.com/imariom/go-users-api/problem"
+)
+
+// jsonContentType sets the response Content-Type to application/json ahead
+// of time and rejects request bodies that claim a different content type,
+// since every endpoint in this API exchanges JSON.
+func jsonContentType(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		if r.ContentLength > 0 {
+			if ct := r.Header.Get("Content-Type"); ct != "" && ct != "application/json" {
+				problem.Write(rw, r, http.StatusUnsupportedMediaType, "unsupported content type", "Content-Type must be application/json")
+				return
+			}
+		}
+
+		rw.Header().Set("Content-Type", "application/json")
+		next.ServeHTTP(rw, r)
+	})
+}
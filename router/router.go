@@ -0,0 +1,107 @@
+// Package router wires UserHandler's routes onto a chi.Router, replacing
+// the per-request regexp matching UserHandler used to do itself.
+package router
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/cors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/imariom/go-users-api/auth"
+	"github.com/imariom/go-users-api/metrics"
+)
+
+// handler is the subset of *main.UserHandler the router depends on. It's
+// defined here, rather than imported, to avoid a router->main import cycle;
+// main.UserHandler satisfies it.
+type handler interface {
+	ListUsers(rw http.ResponseWriter, r *http.Request)
+	GetUser(rw http.ResponseWriter, r *http.Request)
+	CreateUser(rw http.ResponseWriter, r *http.Request)
+	UpdateUser(rw http.ResponseWriter, r *http.Request)
+	PatchUser(rw http.ResponseWriter, r *http.Request)
+	DeleteUser(rw http.ResponseWriter, r *http.Request)
+	Login(rw http.ResponseWriter, r *http.Request)
+	Logout(rw http.ResponseWriter, r *http.Request)
+	CreateToken(rw http.ResponseWriter, r *http.Request)
+	DeleteToken(rw http.ResponseWriter, r *http.Request)
+	Secret() []byte
+}
+
+// revoker is satisfied by the token revocation tracker a handler exposes.
+type revoker interface {
+	auth.TokenRevoker
+}
+
+// New mounts h's routes onto a chi.Router and returns it ready to be passed
+// to http.ListenAndServe. revoked is used by auth.Middleware to reject
+// revoked tokens; it is typically h.Revoked. allowedOrigins lists the
+// origins permitted to make credentialed cross-origin requests (e.g. the
+// login flow's access-token cookie); a nil or empty list disables
+// cross-origin access entirely, since the CORS spec forbids pairing a
+// wildcard origin with credentials.
+func New(h handler, revoked revoker, allowedOrigins []string) http.Handler {
+	r := chi.NewRouter()
+
+	r.Use(chimiddleware.RequestID)
+	r.Use(chimiddleware.RealIP)
+	r.Use(chimiddleware.Recoverer)
+	r.Use(chimiddleware.Timeout(30 * time.Second))
+	// cors.Handler must wrap every middleware capable of rejecting a
+	// request (jsonContentType's 415, auth.Middleware's 401, ...) so a
+	// rejected cross-origin request still carries CORS headers; otherwise
+	// the browser hides the real error behind an opaque CORS failure.
+	corsOptions := cors.Options{
+		AllowedOrigins:   allowedOrigins,
+		AllowedMethods:   []string{"GET", "POST", "PUT", "PATCH", "DELETE"},
+		AllowedHeaders:   []string{"Authorization", "Content-Type"},
+		AllowCredentials: true,
+	}
+	if len(allowedOrigins) == 0 {
+		// cors treats an empty AllowedOrigins as "every origin" unless an
+		// AllowOriginFunc is set, so reject every origin explicitly.
+		corsOptions.AllowOriginFunc = func(*http.Request, string) bool { return false }
+	}
+	r.Use(cors.Handler(corsOptions))
+	r.Use(auth.WithUserIDBox)
+	r.Use(metrics.Middleware)
+	r.Use(requestLogger)
+	r.Use(jsonContentType)
+
+	r.Handle("/metrics", promhttp.Handler())
+
+	r.Post("/login", h.Login)
+	r.Post("/logout", h.Logout)
+
+	r.Route("/users", func(r chi.Router) {
+		// Registration can't require the access token it's responsible for
+		// issuing, so POST / is the one route in this group left
+		// unauthenticated.
+		r.Post("/", h.CreateUser)
+
+		r.Group(func(r chi.Router) {
+			authenticate := func(next http.Handler) http.Handler {
+				return auth.Middleware(next, h.Secret(), revoked)
+			}
+			r.Use(authenticate)
+
+			r.Get("/", h.ListUsers)
+
+			r.Route("/{id}", func(r chi.Router) {
+				r.Get("/", h.GetUser)
+				r.Put("/", h.UpdateUser)
+				r.Patch("/", h.PatchUser)
+				r.Delete("/", h.DeleteUser)
+
+				r.Post("/tokens", h.CreateToken)
+				r.Delete("/tokens/{tokenID}", h.DeleteToken)
+			})
+		})
+	})
+
+	return r
+}
@@ -0,0 +1,125 @@
+package router
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/imariom/go-users-api/problem"
+)
+
+type stubHandler struct{}
+
+func (stubHandler) ListUsers(http.ResponseWriter, *http.Request)   {}
+func (stubHandler) GetUser(http.ResponseWriter, *http.Request)     {}
+func (stubHandler) CreateUser(http.ResponseWriter, *http.Request)  {}
+func (stubHandler) UpdateUser(http.ResponseWriter, *http.Request)  {}
+func (stubHandler) PatchUser(http.ResponseWriter, *http.Request)   {}
+func (stubHandler) DeleteUser(http.ResponseWriter, *http.Request)  {}
+func (stubHandler) Login(http.ResponseWriter, *http.Request)       {}
+func (stubHandler) Logout(http.ResponseWriter, *http.Request)      {}
+func (stubHandler) CreateToken(http.ResponseWriter, *http.Request) {}
+func (stubHandler) DeleteToken(http.ResponseWriter, *http.Request) {}
+func (stubHandler) Secret() []byte                                 { return []byte("test-secret") }
+
+type stubRevoker struct{}
+
+func (stubRevoker) IsRevoked(string) bool { return false }
+
+// TestNew_CORSNeverPairsWildcardWithCredentials guards against the CORS
+// spec violation of advertising Access-Control-Allow-Credentials alongside
+// a wildcard Access-Control-Allow-Origin: browsers refuse to expose such
+// responses to JS, breaking the cookie-based login flow for any real
+// cross-origin client.
+func TestNew_CORSNeverPairsWildcardWithCredentials(t *testing.T) {
+	mux := New(stubHandler{}, stubRevoker{}, []string{"https://app.example.com"})
+
+	req := httptest.NewRequest(http.MethodOptions, "/users", nil)
+	req.Header.Set("Origin", "https://evil.example.com")
+	req.Header.Set("Access-Control-Request-Method", "GET")
+	rw := httptest.NewRecorder()
+
+	mux.ServeHTTP(rw, req)
+
+	if got := rw.Header().Get("Access-Control-Allow-Origin"); got == "*" {
+		t.Fatalf("Access-Control-Allow-Origin = %q, must never be \"*\" when credentials are allowed", got)
+	}
+}
+
+// TestNew_CreateUserDoesNotRequireAuth guards against regressing to a state
+// where POST /users sits behind auth.Middleware: that would make it
+// impossible to create the first user, since the only way to obtain a
+// token is to already have an account.
+func TestNew_CreateUserDoesNotRequireAuth(t *testing.T) {
+	mux := New(stubHandler{}, stubRevoker{}, nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/users", http.NoBody)
+	rw := httptest.NewRecorder()
+
+	mux.ServeHTTP(rw, req)
+
+	if rw.Code == http.StatusUnauthorized {
+		t.Fatalf("POST /users status = %d, must not require authentication", rw.Code)
+	}
+}
+
+// TestNew_ListUsersRequiresAuth guards the other side of the same route
+// group split: every /users route other than registration must still
+// require a valid access token.
+func TestNew_ListUsersRequiresAuth(t *testing.T) {
+	mux := New(stubHandler{}, stubRevoker{}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/users", http.NoBody)
+	rw := httptest.NewRecorder()
+
+	mux.ServeHTTP(rw, req)
+
+	if rw.Code != http.StatusUnauthorized {
+		t.Fatalf("GET /users status = %d, want %d", rw.Code, http.StatusUnauthorized)
+	}
+}
+
+// TestNew_UnsupportedContentTypeIsProblemJSON guards against the
+// jsonContentType rejection regressing to a plain-text body, since every
+// other rejected request in this API responds application/problem+json.
+func TestNew_UnsupportedContentTypeIsProblemJSON(t *testing.T) {
+	mux := New(stubHandler{}, stubRevoker{}, nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/login", strings.NewReader("<xml/>"))
+	req.Header.Set("Content-Type", "application/xml")
+	rw := httptest.NewRecorder()
+
+	mux.ServeHTTP(rw, req)
+
+	if rw.Code != http.StatusUnsupportedMediaType {
+		t.Fatalf("status = %d, want %d", rw.Code, http.StatusUnsupportedMediaType)
+	}
+	if ct := rw.Header().Get("Content-Type"); ct != problem.ContentType {
+		t.Fatalf("Content-Type = %q, want %q", ct, problem.ContentType)
+	}
+}
+
+// TestNew_RejectedRequestStillCarriesCORSHeaders guards against
+// cors.Handler being mounted innermost to a rejection-capable middleware
+// like jsonContentType: if jsonContentType's 415 short-circuits before
+// cors.Handler ever runs, an allowed cross-origin caller never sees
+// Access-Control-Allow-Origin and the browser hides the real error behind
+// an opaque CORS failure instead.
+func TestNew_RejectedRequestStillCarriesCORSHeaders(t *testing.T) {
+	mux := New(stubHandler{}, stubRevoker{}, []string{"https://app.example.com"})
+
+	req := httptest.NewRequest(http.MethodPost, "/login", strings.NewReader("<xml/>"))
+	req.Header.Set("Content-Type", "application/xml")
+	req.Header.Set("Origin", "https://app.example.com")
+	rw := httptest.NewRecorder()
+
+	mux.ServeHTTP(rw, req)
+
+	if rw.Code != http.StatusUnsupportedMediaType {
+		t.Fatalf("status = %d, want %d", rw.Code, http.StatusUnsupportedMediaType)
+	}
+	if got := rw.Header().Get("Access-Control-Allow-Origin"); got != "https://app.example.com" {
+		t.Fatalf("Access-Control-Allow-Origin = %q, want %q", got, "https://app.example.com")
+	}
+}
@@ -0,0 +1,23 @@
+package store
+
+import (
+	"regexp"
+	"strings"
+)
+
+// MatchGlob reports whether s matches pattern, where "*" in pattern matches
+// any run of characters. It is used by List implementations to support
+// filters like "?email=*@example.com".
+func MatchGlob(pattern, s string) bool {
+	if !strings.Contains(pattern, "*") {
+		return pattern == s
+	}
+
+	parts := strings.Split(pattern, "*")
+	for i, p := range parts {
+		parts[i] = regexp.QuoteMeta(p)
+	}
+
+	re := regexp.MustCompile("^" + strings.Join(parts, ".*") + "$")
+	return re.MatchString(s)
+}
@@ -0,0 +1,183 @@
+// Package memstore is an in-memory store.UserStore implementation. It keeps
+// the original go-users-api behavior (a mutex-guarded map) and is the
+// default backend when no other is configured.
+package memstore
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/imariom/go-users-api/store"
+)
+
+// Store is an in-memory, concurrency-safe store.UserStore.
+type Store struct {
+	mu     sync.RWMutex
+	byID   map[int]*store.User
+	nextID int
+}
+
+// New returns an empty Store.
+func New() *Store {
+	return &Store{
+		byID: map[int]*store.User{},
+	}
+}
+
+// Get implements store.UserStore.
+func (s *Store) Get(id int) (*store.User, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	u, ok := s.byID[id]
+	if !ok {
+		return nil, store.ErrNotFound
+	}
+
+	copied := *u
+	return &copied, nil
+}
+
+// GetByUsername implements store.UserStore.
+func (s *Store) GetByUsername(username string) (*store.User, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, u := range s.byID {
+		if u.Username == username {
+			copied := *u
+			return &copied, nil
+		}
+	}
+
+	return nil, store.ErrNotFound
+}
+
+// List implements store.UserStore.
+func (s *Store) List(ctx context.Context, filter store.ListOptions) ([]*store.User, int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	users := make([]*store.User, 0, len(s.byID))
+	for _, u := range s.byID {
+		if filter.Username != "" && !store.MatchGlob(filter.Username, u.Username) {
+			continue
+		}
+		if filter.Email != "" && !store.MatchGlob(filter.Email, u.Email) {
+			continue
+		}
+		copied := *u
+		users = append(users, &copied)
+	}
+
+	sortUsers(users, filter.Sort)
+
+	total := len(users)
+
+	if filter.Offset > 0 {
+		if filter.Offset >= len(users) {
+			return []*store.User{}, total, nil
+		}
+		users = users[filter.Offset:]
+	}
+	if filter.Limit > 0 && filter.Limit < len(users) {
+		users = users[:filter.Limit]
+	}
+
+	return users, total, nil
+}
+
+// Create implements store.UserStore.
+func (s *Store) Create(u *store.User) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.findDuplicate(-1, u.Username, u.Email) {
+		return 0, store.ErrConflict
+	}
+
+	id := s.nextID
+	s.nextID++
+
+	copied := *u
+	copied.ID = id
+	s.byID[id] = &copied
+
+	return id, nil
+}
+
+// Update implements store.UserStore.
+func (s *Store) Update(id int, u *store.User) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.byID[id]; !ok {
+		return store.ErrNotFound
+	}
+
+	if s.findDuplicate(id, u.Username, u.Email) {
+		return store.ErrConflict
+	}
+
+	copied := *u
+	copied.ID = id
+	s.byID[id] = &copied
+
+	return nil
+}
+
+// Delete implements store.UserStore.
+func (s *Store) Delete(id int) (*store.User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	u, ok := s.byID[id]
+	if !ok {
+		return nil, store.ErrNotFound
+	}
+
+	delete(s.byID, id)
+
+	return u, nil
+}
+
+// findDuplicate reports whether another user (one whose ID isn't excludeID)
+// already has username or email. Callers must hold s.mu.
+func (s *Store) findDuplicate(excludeID int, username, email string) bool {
+	for id, u := range s.byID {
+		if id == excludeID {
+			continue
+		}
+		if u.Username == username || u.Email == email {
+			return true
+		}
+	}
+	return false
+}
+
+// sortUsers orders users in place according to spec, a field name optionally
+// prefixed with "-" for descending order. Unknown or empty specs leave the
+// order untouched.
+func sortUsers(users []*store.User, spec string) {
+	desc := strings.HasPrefix(spec, "-")
+	field := strings.TrimPrefix(spec, "-")
+
+	var less func(a, b *store.User) bool
+	switch field {
+	case "id":
+		less = func(a, b *store.User) bool { return a.ID < b.ID }
+	case "username":
+		less = func(a, b *store.User) bool { return a.Username < b.Username }
+	default:
+		return
+	}
+
+	sort.Slice(users, func(i, j int) bool {
+		if desc {
+			return less(users[j], users[i])
+		}
+		return less(users[i], users[j])
+	})
+}
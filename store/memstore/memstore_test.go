@@ -0,0 +1,11 @@
+package memstore
+
+import (
+	"testing"
+
+	"github.com/imariom/go-users-api/store/storetest"
+)
+
+func TestStoreConformance(t *testing.T) {
+	storetest.Run(t, New())
+}
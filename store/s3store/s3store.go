@@ -0,0 +1,349 @@
+// Package s3store is an optional store.UserStore implementation that keeps
+// each user as a JSON object in an S3-compatible bucket, one object per
+// user plus an index object listing known IDs. It trades List/filter
+// performance for zero operational footprint beyond a bucket.
+package s3store
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+
+	"github.com/imariom/go-users-api/store"
+)
+
+// indexKey is the object holding the sorted list of known user IDs.
+const indexKey = "users/index.json"
+
+// storedUser is the on-disk JSON representation of a store.User object in
+// this backend. store.User.Tokens is tagged json:"-" so API responses
+// never leak it, but s3store has no column-level control like sqlstore
+// does, so it needs an explicit, non-"-" field to actually persist
+// personal access tokens; the outer Tokens field shadows the embedded,
+// un-marshalable one for encoding/json purposes.
+type storedUser struct {
+	store.User
+	Tokens []store.PersonalAccessToken `json:"tokens"`
+}
+
+// Store is an S3-backed store.UserStore.
+type Store struct {
+	client *s3.Client
+	bucket string
+
+	// mu serializes index read-modify-write cycles; S3 has no multi-object
+	// transactions, so Create/Delete must not race each other locally.
+	mu sync.Mutex
+}
+
+// New returns a Store that persists users in bucket via client.
+func New(client *s3.Client, bucket string) *Store {
+	return &Store{client: client, bucket: bucket}
+}
+
+func (s *Store) objectKey(id int) string {
+	return fmt.Sprintf("users/%d.json", id)
+}
+
+// Get implements store.UserStore.
+func (s *Store) Get(id int) (*store.User, error) {
+	ctx := context.Background()
+
+	out, err := s.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(s.objectKey(id)),
+	})
+	if err != nil {
+		if isNotFound(err) {
+			return nil, store.ErrNotFound
+		}
+		return nil, err
+	}
+	defer out.Body.Close()
+
+	stored := storedUser{}
+	if err := json.NewDecoder(out.Body).Decode(&stored); err != nil {
+		return nil, err
+	}
+
+	u := stored.User
+	u.Tokens = stored.Tokens
+	return &u, nil
+}
+
+// GetByUsername implements store.UserStore. Like List, it has no query
+// pushdown on this backend and must fetch every user object to find a
+// match.
+func (s *Store) GetByUsername(username string) (*store.User, error) {
+	ctx := context.Background()
+
+	ids, err := s.readIndex(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, id := range ids {
+		u, err := s.Get(id)
+		if err != nil {
+			return nil, err
+		}
+		if u.Username == username {
+			return u, nil
+		}
+	}
+
+	return nil, store.ErrNotFound
+}
+
+// List implements store.UserStore. It fetches the index, then every
+// matching user object; there is no query pushdown on this backend.
+func (s *Store) List(ctx context.Context, filter store.ListOptions) ([]*store.User, int, error) {
+	ids, err := s.readIndex(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	users := make([]*store.User, 0, len(ids))
+	for _, id := range ids {
+		u, err := s.Get(id)
+		if err != nil {
+			return nil, 0, err
+		}
+		if filter.Username != "" && !store.MatchGlob(filter.Username, u.Username) {
+			continue
+		}
+		if filter.Email != "" && !store.MatchGlob(filter.Email, u.Email) {
+			continue
+		}
+		users = append(users, u)
+	}
+
+	sortUsers(users, filter.Sort)
+
+	total := len(users)
+
+	if filter.Offset > 0 {
+		if filter.Offset >= len(users) {
+			return []*store.User{}, total, nil
+		}
+		users = users[filter.Offset:]
+	}
+	if filter.Limit > 0 && filter.Limit < len(users) {
+		users = users[:filter.Limit]
+	}
+
+	return users, total, nil
+}
+
+// sortUsers orders users in place according to spec, a field name
+// optionally prefixed with "-" for descending order. Unknown or empty specs
+// leave the order untouched.
+func sortUsers(users []*store.User, spec string) {
+	desc := strings.HasPrefix(spec, "-")
+	field := strings.TrimPrefix(spec, "-")
+
+	var less func(a, b *store.User) bool
+	switch field {
+	case "id":
+		less = func(a, b *store.User) bool { return a.ID < b.ID }
+	case "username":
+		less = func(a, b *store.User) bool { return a.Username < b.Username }
+	default:
+		return
+	}
+
+	sort.Slice(users, func(i, j int) bool {
+		if desc {
+			return less(users[j], users[i])
+		}
+		return less(users[i], users[j])
+	})
+}
+
+// Create implements store.UserStore.
+func (s *Store) Create(u *store.User) (int, error) {
+	ctx := context.Background()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ids, err := s.readIndex(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	if dup, err := s.findDuplicate(ctx, ids, -1, u.Username, u.Email); err != nil {
+		return 0, err
+	} else if dup {
+		return 0, store.ErrConflict
+	}
+
+	id := 0
+	for _, existing := range ids {
+		if existing >= id {
+			id = existing + 1
+		}
+	}
+
+	copied := *u
+	copied.ID = id
+
+	if err := s.putUser(ctx, &copied); err != nil {
+		return 0, err
+	}
+
+	ids = append(ids, id)
+	if err := s.writeIndex(ctx, ids); err != nil {
+		return 0, err
+	}
+
+	return id, nil
+}
+
+// Update implements store.UserStore.
+func (s *Store) Update(id int, u *store.User) error {
+	ctx := context.Background()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.Get(id); err != nil {
+		return err
+	}
+
+	ids, err := s.readIndex(ctx)
+	if err != nil {
+		return err
+	}
+
+	if dup, err := s.findDuplicate(ctx, ids, id, u.Username, u.Email); err != nil {
+		return err
+	} else if dup {
+		return store.ErrConflict
+	}
+
+	copied := *u
+	copied.ID = id
+
+	return s.putUser(ctx, &copied)
+}
+
+// findDuplicate reports whether another user (one whose ID isn't excludeID)
+// already has username or email. Callers must hold s.mu.
+func (s *Store) findDuplicate(ctx context.Context, ids []int, excludeID int, username, email string) (bool, error) {
+	for _, id := range ids {
+		if id == excludeID {
+			continue
+		}
+		u, err := s.Get(id)
+		if err != nil {
+			return false, err
+		}
+		if u.Username == username || u.Email == email {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// Delete implements store.UserStore.
+func (s *Store) Delete(id int) (*store.User, error) {
+	ctx := context.Background()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	u, err := s.Get(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.client.DeleteObject(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(s.objectKey(id)),
+	}); err != nil {
+		return nil, err
+	}
+
+	ids, err := s.readIndex(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ids = removeID(ids, id)
+
+	return u, s.writeIndex(ctx, ids)
+}
+
+func (s *Store) putUser(ctx context.Context, u *store.User) error {
+	body, err := json.Marshal(storedUser{User: *u, Tokens: u.Tokens})
+	if err != nil {
+		return err
+	}
+
+	_, err = s.client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(s.objectKey(u.ID)),
+		Body:   bytes.NewReader(body),
+	})
+	return err
+}
+
+func (s *Store) readIndex(ctx context.Context) ([]int, error) {
+	out, err := s.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(indexKey),
+	})
+	if err != nil {
+		if isNotFound(err) {
+			return []int{}, nil
+		}
+		return nil, err
+	}
+	defer out.Body.Close()
+
+	var ids []int
+	if err := json.NewDecoder(out.Body).Decode(&ids); err != nil {
+		return nil, err
+	}
+
+	sort.Ints(ids)
+	return ids, nil
+}
+
+func (s *Store) writeIndex(ctx context.Context, ids []int) error {
+	sort.Ints(ids)
+
+	body, err := json.Marshal(ids)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(indexKey),
+		Body:   bytes.NewReader(body),
+	})
+	return err
+}
+
+func removeID(ids []int, target int) []int {
+	out := ids[:0]
+	for _, id := range ids {
+		if id != target {
+			out = append(out, id)
+		}
+	}
+	return out
+}
+
+// isNotFound reports whether err is an S3 "NoSuchKey" error.
+func isNotFound(err error) bool {
+	return err != nil && strings.Contains(err.Error(), "NoSuchKey")
+}
@@ -0,0 +1,31 @@
+//go:build integration
+
+package s3store
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+
+	"github.com/imariom/go-users-api/store/storetest"
+)
+
+// TestStoreConformance requires a real (or locally emulated, e.g. MinIO) S3
+// bucket named by S3STORE_TEST_BUCKET and standard AWS credentials; it is
+// only built with `go test -tags=integration`.
+func TestStoreConformance(t *testing.T) {
+	bucket := os.Getenv("S3STORE_TEST_BUCKET")
+	if bucket == "" {
+		t.Skip("S3STORE_TEST_BUCKET not set")
+	}
+
+	cfg, err := config.LoadDefaultConfig(context.Background())
+	if err != nil {
+		t.Fatalf("config.LoadDefaultConfig() error = %v", err)
+	}
+
+	storetest.Run(t, New(s3.NewFromConfig(cfg), bucket))
+}
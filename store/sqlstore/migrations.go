@@ -0,0 +1,51 @@
+package sqlstore
+
+// createUsersTable returns the DDL that creates the users table for
+// dialect, used by Store.Migrate.
+func createUsersTable(dialect Dialect) string {
+	if dialect == Postgres {
+		return `
+CREATE TABLE IF NOT EXISTS users (
+	id       SERIAL PRIMARY KEY,
+	username TEXT NOT NULL UNIQUE,
+	password TEXT NOT NULL,
+	email    TEXT NOT NULL UNIQUE
+);`
+	}
+
+	return `
+CREATE TABLE IF NOT EXISTS users (
+	id       INTEGER PRIMARY KEY AUTOINCREMENT,
+	username TEXT NOT NULL UNIQUE,
+	password TEXT NOT NULL,
+	email    TEXT NOT NULL UNIQUE
+);`
+}
+
+// createTokensTable returns the DDL that creates the personal_access_tokens
+// table for dialect, used by Store.Migrate. Without it, personal access
+// tokens minted via POST /users/{id}/tokens would have nowhere to live on
+// this backend.
+func createTokensTable(dialect Dialect) string {
+	if dialect == Postgres {
+		return `
+CREATE TABLE IF NOT EXISTS personal_access_tokens (
+	id         TEXT PRIMARY KEY,
+	user_id    INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+	name       TEXT NOT NULL,
+	token      TEXT NOT NULL,
+	created_at TIMESTAMPTZ NOT NULL,
+	expires_at TIMESTAMPTZ NOT NULL
+);`
+	}
+
+	return `
+CREATE TABLE IF NOT EXISTS personal_access_tokens (
+	id         TEXT PRIMARY KEY,
+	user_id    INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+	name       TEXT NOT NULL,
+	token      TEXT NOT NULL,
+	created_at DATETIME NOT NULL,
+	expires_at DATETIME NOT NULL
+);`
+}
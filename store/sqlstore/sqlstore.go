@@ -0,0 +1,322 @@
+// Package sqlstore is a store.UserStore implementation backed by
+// database/sql, supporting both PostgreSQL and SQLite. The SQL dialect
+// differences (placeholder syntax, retrieving the inserted ID) are handled internally
+// so callers interact with a single Store type regardless of backend.
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/imariom/go-users-api/store"
+)
+
+// Dialect identifies which SQL engine a Store talks to.
+type Dialect string
+
+const (
+	// Postgres targets PostgreSQL via github.com/lib/pq.
+	Postgres Dialect = "postgres"
+
+	// SQLite targets SQLite via modernc.org/sqlite.
+	SQLite Dialect = "sqlite"
+)
+
+// Store is a database/sql backed store.UserStore.
+type Store struct {
+	db      *sql.DB
+	dialect Dialect
+}
+
+// New wraps db as a store.UserStore. Migrate must be called once before use
+// so the users table exists.
+func New(db *sql.DB, dialect Dialect) *Store {
+	return &Store{db: db, dialect: dialect}
+}
+
+// Migrate creates the users and personal_access_tokens tables and their
+// indexes if they don't already exist.
+func (s *Store) Migrate(ctx context.Context) error {
+	if _, err := s.db.ExecContext(ctx, createUsersTable(s.dialect)); err != nil {
+		return err
+	}
+	_, err := s.db.ExecContext(ctx, createTokensTable(s.dialect))
+	return err
+}
+
+// Get implements store.UserStore.
+func (s *Store) Get(id int) (*store.User, error) {
+	row := s.db.QueryRow(s.rebind("SELECT id, username, password, email FROM users WHERE id = ?"), id)
+
+	u := &store.User{}
+	if err := row.Scan(&u.ID, &u.Username, &u.Password, &u.Email); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, store.ErrNotFound
+		}
+		return nil, err
+	}
+
+	tokens, err := s.tokensForUser(id)
+	if err != nil {
+		return nil, err
+	}
+	u.Tokens = tokens
+
+	return u, nil
+}
+
+// GetByUsername implements store.UserStore.
+func (s *Store) GetByUsername(username string) (*store.User, error) {
+	row := s.db.QueryRow(s.rebind("SELECT id, username, password, email FROM users WHERE username = ?"), username)
+
+	u := &store.User{}
+	if err := row.Scan(&u.ID, &u.Username, &u.Password, &u.Email); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, store.ErrNotFound
+		}
+		return nil, err
+	}
+
+	tokens, err := s.tokensForUser(u.ID)
+	if err != nil {
+		return nil, err
+	}
+	u.Tokens = tokens
+
+	return u, nil
+}
+
+// tokensForUser returns the personal access tokens persisted for userID.
+func (s *Store) tokensForUser(userID int) ([]store.PersonalAccessToken, error) {
+	rows, err := s.db.Query(s.rebind(
+		"SELECT id, name, token, created_at, expires_at FROM personal_access_tokens WHERE user_id = ? ORDER BY created_at"),
+		userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tokens []store.PersonalAccessToken
+	for rows.Next() {
+		var t store.PersonalAccessToken
+		if err := rows.Scan(&t.ID, &t.Name, &t.Token, &t.CreatedAt, &t.ExpiresAt); err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, t)
+	}
+
+	return tokens, rows.Err()
+}
+
+// replaceTokens overwrites the persisted personal access tokens for userID
+// with tokens, so Update can treat store.User.Tokens as the source of truth.
+func (s *Store) replaceTokens(userID int, tokens []store.PersonalAccessToken) error {
+	if _, err := s.db.Exec(s.rebind("DELETE FROM personal_access_tokens WHERE user_id = ?"), userID); err != nil {
+		return err
+	}
+
+	insert := s.rebind("INSERT INTO personal_access_tokens (id, user_id, name, token, created_at, expires_at) VALUES (?, ?, ?, ?, ?, ?)")
+	for _, t := range tokens {
+		if _, err := s.db.Exec(insert, t.ID, userID, t.Name, t.Token, t.CreatedAt, t.ExpiresAt); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// List implements store.UserStore, pushing filtering, sorting and
+// pagination down to the database.
+func (s *Store) List(ctx context.Context, filter store.ListOptions) ([]*store.User, int, error) {
+	where, args := whereClause(filter)
+
+	var total int
+	countQuery := s.rebind("SELECT COUNT(*) FROM users" + where)
+	if err := s.db.QueryRowContext(ctx, countQuery, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	query := "SELECT id, username, password, email FROM users" + where + orderBy(filter.Sort)
+
+	if filter.Limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, filter.Limit)
+	}
+	if filter.Offset > 0 {
+		query += " OFFSET ?"
+		args = append(args, filter.Offset)
+	}
+
+	rows, err := s.db.QueryContext(ctx, s.rebind(query), args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	users := make([]*store.User, 0)
+	for rows.Next() {
+		u := &store.User{}
+		if err := rows.Scan(&u.ID, &u.Username, &u.Password, &u.Email); err != nil {
+			return nil, 0, err
+		}
+		users = append(users, u)
+	}
+
+	return users, total, rows.Err()
+}
+
+// whereClause builds the " WHERE ..." fragment (or "" when filter has no
+// constraints) and its positional args for filter. A "*" in Username or
+// Email is translated to the SQL "%" wildcard.
+func whereClause(filter store.ListOptions) (string, []any) {
+	var conds []string
+	var args []any
+
+	if filter.Username != "" {
+		if strings.Contains(filter.Username, "*") {
+			conds = append(conds, "username LIKE ?")
+			args = append(args, strings.ReplaceAll(filter.Username, "*", "%"))
+		} else {
+			conds = append(conds, "username = ?")
+			args = append(args, filter.Username)
+		}
+	}
+	if filter.Email != "" {
+		if strings.Contains(filter.Email, "*") {
+			conds = append(conds, "email LIKE ?")
+			args = append(args, strings.ReplaceAll(filter.Email, "*", "%"))
+		} else {
+			conds = append(conds, "email = ?")
+			args = append(args, filter.Email)
+		}
+	}
+
+	if len(conds) == 0 {
+		return "", args
+	}
+
+	return " WHERE " + strings.Join(conds, " AND "), args
+}
+
+// Create implements store.UserStore. IDs are assigned by the database's
+// auto-increment column, not the legacy global counter.
+func (s *Store) Create(u *store.User) (int, error) {
+	query := s.rebind("INSERT INTO users (username, password, email) VALUES (?, ?, ?)")
+
+	if s.dialect == Postgres {
+		var id int
+		err := s.db.QueryRow(query+" RETURNING id", u.Username, u.Password, u.Email).Scan(&id)
+		if isUniqueViolation(err) {
+			return 0, store.ErrConflict
+		}
+		if err != nil {
+			return 0, err
+		}
+		return id, s.replaceTokens(id, u.Tokens)
+	}
+
+	res, err := s.db.Exec(query, u.Username, u.Password, u.Email)
+	if isUniqueViolation(err) {
+		return 0, store.ErrConflict
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	id64, err := res.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+	id := int(id64)
+
+	return id, s.replaceTokens(id, u.Tokens)
+}
+
+// Update implements store.UserStore.
+func (s *Store) Update(id int, u *store.User) error {
+	query := s.rebind("UPDATE users SET username = ?, password = ?, email = ? WHERE id = ?")
+
+	res, err := s.db.Exec(query, u.Username, u.Password, u.Email, id)
+	if isUniqueViolation(err) {
+		return store.ErrConflict
+	}
+	if err != nil {
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return store.ErrNotFound
+	}
+
+	return s.replaceTokens(id, u.Tokens)
+}
+
+// Delete implements store.UserStore.
+func (s *Store) Delete(id int) (*store.User, error) {
+	u, err := s.Get(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.db.Exec(s.rebind("DELETE FROM personal_access_tokens WHERE user_id = ?"), id); err != nil {
+		return nil, err
+	}
+
+	_, err = s.db.Exec(s.rebind("DELETE FROM users WHERE id = ?"), id)
+	return u, err
+}
+
+// rebind rewrites "?" placeholders to "$1", "$2", ... for Postgres, which
+// doesn't support the "?" syntax used by SQLite and most other drivers.
+func (s *Store) rebind(query string) string {
+	if s.dialect != Postgres {
+		return query
+	}
+
+	var b strings.Builder
+	n := 0
+	for _, r := range query {
+		if r == '?' {
+			n++
+			fmt.Fprintf(&b, "$%d", n)
+			continue
+		}
+		b.WriteRune(r)
+	}
+
+	return b.String()
+}
+
+// orderBy translates a ListOptions.Sort spec into a SQL ORDER BY clause.
+func orderBy(spec string) string {
+	desc := strings.HasPrefix(spec, "-")
+	field := strings.TrimPrefix(spec, "-")
+
+	switch field {
+	case "id", "username":
+		if desc {
+			return " ORDER BY " + field + " DESC"
+		}
+		return " ORDER BY " + field + " ASC"
+	default:
+		return " ORDER BY id ASC"
+	}
+}
+
+// isUniqueViolation reports whether err represents a unique constraint
+// violation on either supported dialect.
+func isUniqueViolation(err error) bool {
+	if err == nil {
+		return false
+	}
+	msg := err.Error()
+	return strings.Contains(msg, "UNIQUE constraint failed") || // sqlite
+		strings.Contains(msg, "duplicate key value violates unique constraint") // postgres
+}
@@ -0,0 +1,26 @@
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	_ "modernc.org/sqlite"
+
+	"github.com/imariom/go-users-api/store/storetest"
+)
+
+func TestStoreConformance(t *testing.T) {
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("sql.Open() error = %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	s := New(db, SQLite)
+	if err := s.Migrate(context.Background()); err != nil {
+		t.Fatalf("Migrate() error = %v", err)
+	}
+
+	storetest.Run(t, s)
+}
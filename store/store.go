@@ -0,0 +1,102 @@
+// Package store defines the persistence-layer contract for the
+// go-users-api service and the domain types it operates on. Concrete
+// backends (memstore, sqlstore, s3store) implement UserStore so
+// UserHandler never depends on how or where users are actually kept.
+package store
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrNotFound is returned by UserStore methods when the requested user does
+// not exist.
+var ErrNotFound = errors.New("store: user not found")
+
+// ErrConflict is returned by Create and Update when the operation would
+// violate a uniqueness constraint (e.g. duplicate username or email).
+var ErrConflict = errors.New("store: username or email already in use")
+
+// User represents a real world user of a particular system.
+type User struct {
+	// ID is the API automatically generated user identification number.
+	ID int `json:"id"`
+
+	// Username is the username of the user of the API.
+	Username string `json:"username" validate:"required,min=3"`
+
+	// Password is the bcrypt hash of the user's password.
+	Password string `json:"password" validate:"required,min=8"`
+
+	// Email is the email of the user of the API.
+	Email string `json:"email" validate:"required,email"`
+
+	// Tokens are the personal access tokens that have been minted for this
+	// user.
+	Tokens []PersonalAccessToken `json:"-"`
+}
+
+// PersonalAccessToken is a long-lived token a user can mint to authenticate
+// API requests without logging in with a username and password.
+type PersonalAccessToken struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Token     string    `json:"token,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// ListOptions controls filtering, sorting and pagination for List.
+type ListOptions struct {
+	// Limit caps the number of users returned. Zero means "backend default".
+	Limit int
+
+	// Offset skips this many matching users before collecting Limit of them.
+	Offset int
+
+	// Sort is a field name, optionally prefixed with "-" for descending
+	// order, e.g. "username" or "-id".
+	Sort string
+
+	// Username, if set, restricts results to users whose username matches
+	// this value. A "*" in the value matches any run of characters, see
+	// MatchGlob.
+	Username string
+
+	// Email, if set, restricts results to users whose email matches this
+	// value. A "*" in the value matches any run of characters, see
+	// MatchGlob.
+	Email string
+}
+
+// UserStore is the persistence contract that UserHandler depends on. Every
+// backend under store/ must implement it with identical semantics: Get and
+// Delete return ErrNotFound for a missing ID, Create and Update return
+// ErrConflict on a duplicate username or email.
+type UserStore interface {
+	// Get returns the user with the given ID.
+	Get(id int) (*User, error)
+
+	// GetByUsername returns the user with the given username, matched
+	// exactly (unlike List's Username filter, "*" has no special meaning
+	// here). Callers authenticating a user by username and password must
+	// use this instead of List, which treats Username as a glob and would
+	// let a username of "*" match every user. Returns ErrNotFound if no
+	// user has that exact username.
+	GetByUsername(username string) (*User, error)
+
+	// List returns the users matching filter, along with the total number
+	// of matches before Limit/Offset were applied, so callers can build a
+	// pagination envelope.
+	List(ctx context.Context, filter ListOptions) (users []*User, total int, err error)
+
+	// Create assigns u a new ID, persists it, and returns the assigned ID.
+	Create(u *User) (int, error)
+
+	// Update replaces the user with the given ID with u.
+	Update(id int, u *User) error
+
+	// Delete removes and returns the user with the given ID.
+	Delete(id int) (*User, error)
+}
@@ -0,0 +1,187 @@
+// Package storetest is a conformance suite shared by every store.UserStore
+// backend. Each backend's own test package calls Run against a fresh,
+// empty instance to guarantee identical semantics across implementations.
+package storetest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/imariom/go-users-api/store"
+)
+
+// Run exercises s, a freshly constructed and empty store.UserStore, against
+// the full CRUD contract described by store.UserStore's doc comments.
+func Run(t *testing.T, s store.UserStore) {
+	t.Run("CreateAndGet", func(t *testing.T) {
+		id, err := s.Create(&store.User{Username: "alice", Password: "hash", Email: "alice@example.com"})
+		if err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+
+		got, err := s.Get(id)
+		if err != nil {
+			t.Fatalf("Get(%d) error = %v", id, err)
+		}
+		if got.Username != "alice" || got.Email != "alice@example.com" {
+			t.Fatalf("Get(%d) = %+v, want username=alice email=alice@example.com", id, got)
+		}
+	})
+
+	t.Run("DuplicateOfFirstCreatedUserConflicts", func(t *testing.T) {
+		// alice, created in CreateAndGet above, is the very first user
+		// ever created against this store. Some backends exclude the
+		// record being updated from their duplicate check by ID, and a
+		// badly chosen sentinel for "no record to exclude" can collide
+		// with alice's real (0-based) ID, silently exempting her from
+		// future duplicate checks.
+		if _, err := s.Create(&store.User{Username: "alice", Password: "hash", Email: "alice2@example.com"}); err != store.ErrConflict {
+			t.Fatalf("Create() duplicate of first-created user error = %v, want store.ErrConflict", err)
+		}
+	})
+
+	t.Run("GetMissing", func(t *testing.T) {
+		if _, err := s.Get(999999); err != store.ErrNotFound {
+			t.Fatalf("Get(999999) error = %v, want store.ErrNotFound", err)
+		}
+	})
+
+	t.Run("DuplicateUsernameConflicts", func(t *testing.T) {
+		if _, err := s.Create(&store.User{Username: "bob", Password: "hash", Email: "bob@example.com"}); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+
+		if _, err := s.Create(&store.User{Username: "bob", Password: "hash", Email: "bob2@example.com"}); err != store.ErrConflict {
+			t.Fatalf("Create() duplicate username error = %v, want store.ErrConflict", err)
+		}
+	})
+
+	t.Run("UpdateAndDelete", func(t *testing.T) {
+		id, err := s.Create(&store.User{Username: "carol", Password: "hash", Email: "carol@example.com"})
+		if err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+
+		if err := s.Update(id, &store.User{Username: "carol2", Password: "hash", Email: "carol@example.com"}); err != nil {
+			t.Fatalf("Update(%d) error = %v", id, err)
+		}
+
+		got, err := s.Get(id)
+		if err != nil {
+			t.Fatalf("Get(%d) error = %v", id, err)
+		}
+		if got.Username != "carol2" {
+			t.Fatalf("Get(%d).Username = %q, want carol2", id, got.Username)
+		}
+
+		deleted, err := s.Delete(id)
+		if err != nil {
+			t.Fatalf("Delete(%d) error = %v", id, err)
+		}
+		if deleted.Username != "carol2" {
+			t.Fatalf("Delete(%d) returned %+v, want the deleted user", id, deleted)
+		}
+
+		if _, err := s.Get(id); err != store.ErrNotFound {
+			t.Fatalf("Get(%d) after delete error = %v, want store.ErrNotFound", id, err)
+		}
+	})
+
+	t.Run("GetByUsernameMatchesExactlyNotAsGlob", func(t *testing.T) {
+		if _, err := s.Create(&store.User{Username: "erin", Password: "hash", Email: "erin@example.com"}); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+
+		got, err := s.GetByUsername("erin")
+		if err != nil {
+			t.Fatalf("GetByUsername(erin) error = %v", err)
+		}
+		if got.Username != "erin" {
+			t.Fatalf("GetByUsername(erin).Username = %q, want erin", got.Username)
+		}
+
+		// "*" must not be treated as a glob here: List's Username filter
+		// does, but GetByUsername is used for credential lookup and must
+		// never match a user that wasn't asked for.
+		if _, err := s.GetByUsername("*"); err != store.ErrNotFound {
+			t.Fatalf("GetByUsername(*) error = %v, want store.ErrNotFound", err)
+		}
+	})
+
+	t.Run("ListRespectsLimit", func(t *testing.T) {
+		for i := range 3 {
+			if _, err := s.Create(&store.User{
+				Username: "listuser" + string(rune('a'+i)),
+				Password: "hash",
+				Email:    "listuser" + string(rune('a'+i)) + "@example.com",
+			}); err != nil {
+				t.Fatalf("Create() error = %v", err)
+			}
+		}
+
+		users, total, err := s.List(context.Background(), store.ListOptions{Limit: 2})
+		if err != nil {
+			t.Fatalf("List() error = %v", err)
+		}
+		if len(users) != 2 {
+			t.Fatalf("List() returned %d users, want 2", len(users))
+		}
+		if total < 3 {
+			t.Fatalf("List() total = %d, want at least 3", total)
+		}
+	})
+
+	t.Run("ListFiltersByGlob", func(t *testing.T) {
+		if _, err := s.Create(&store.User{Username: "globuser", Password: "hash", Email: "glob@example.com"}); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+
+		users, _, err := s.List(context.Background(), store.ListOptions{Email: "*@example.com"})
+		if err != nil {
+			t.Fatalf("List() error = %v", err)
+		}
+
+		found := false
+		for _, u := range users {
+			if u.Username == "globuser" {
+				found = true
+			}
+		}
+		if !found {
+			t.Fatalf("List() with glob filter did not return globuser")
+		}
+	})
+
+	t.Run("PersonalAccessTokensPersist", func(t *testing.T) {
+		id, err := s.Create(&store.User{Username: "dave", Password: "hash", Email: "dave@example.com"})
+		if err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+
+		user, err := s.Get(id)
+		if err != nil {
+			t.Fatalf("Get(%d) error = %v", id, err)
+		}
+
+		user.Tokens = append(user.Tokens, store.PersonalAccessToken{
+			ID:        "tok1",
+			Name:      "ci",
+			Token:     "signed-jwt",
+			CreatedAt: time.Now().UTC().Truncate(time.Second),
+			ExpiresAt: time.Now().UTC().Add(24 * time.Hour).Truncate(time.Second),
+		})
+
+		if err := s.Update(id, user); err != nil {
+			t.Fatalf("Update(%d) error = %v", id, err)
+		}
+
+		got, err := s.Get(id)
+		if err != nil {
+			t.Fatalf("Get(%d) error = %v", id, err)
+		}
+		if len(got.Tokens) != 1 || got.Tokens[0].ID != "tok1" {
+			t.Fatalf("Get(%d).Tokens = %+v, want exactly one token with ID tok1", id, got.Tokens)
+		}
+	})
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	_ "github.com/lib/pq"
+	_ "modernc.org/sqlite"
+
+	"github.com/imariom/go-users-api/config"
+	"github.com/imariom/go-users-api/store"
+	"github.com/imariom/go-users-api/store/memstore"
+	"github.com/imariom/go-users-api/store/s3store"
+	"github.com/imariom/go-users-api/store/sqlstore"
+)
+
+// newStore constructs the store.UserStore backend selected by cfg.
+func newStore(cfg *config.Config) (store.UserStore, error) {
+	switch cfg.Store.Backend {
+	case config.Memstore, "":
+		return memstore.New(), nil
+
+	case config.Postgres:
+		return newSQLStore(cfg, sqlstore.Postgres, "postgres")
+
+	case config.SQLite:
+		return newSQLStore(cfg, sqlstore.SQLite, "sqlite")
+
+	case config.S3:
+		return newS3Store(cfg)
+
+	default:
+		return nil, fmt.Errorf("unknown store backend %q", cfg.Store.Backend)
+	}
+}
+
+func newSQLStore(cfg *config.Config, dialect sqlstore.Dialect, driver string) (store.UserStore, error) {
+	db, err := sql.Open(driver, cfg.Store.DSN)
+	if err != nil {
+		return nil, fmt.Errorf("opening %s database: %w", driver, err)
+	}
+
+	s := sqlstore.New(db, dialect)
+	if err := s.Migrate(context.Background()); err != nil {
+		return nil, fmt.Errorf("migrating %s database: %w", driver, err)
+	}
+
+	return s, nil
+}
+
+func newS3Store(cfg *config.Config) (store.UserStore, error) {
+	awsCfg, err := awsconfig.LoadDefaultConfig(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("loading AWS config: %w", err)
+	}
+
+	client := s3.NewFromConfig(awsCfg)
+
+	return s3store.New(client, cfg.Store.Bucket), nil
+}
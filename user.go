@@ -0,0 +1,60 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/imariom/go-users-api/store"
+)
+
+// publicUser is the JSON representation of a store.User returned to
+// clients. It deliberately omits Password so password hashes never leave
+// the server.
+type publicUser struct {
+	ID       int    `json:"id"`
+	Username string `json:"username"`
+	Email    string `json:"email"`
+}
+
+// public returns the representation of u that is safe to send to clients.
+func public(u *store.User) *publicUser {
+	return &publicUser{
+		ID:       u.ID,
+		Username: u.Username,
+		Email:    u.Email,
+	}
+}
+
+// toJSON tries to encode u's public representation to JSON format onto the
+// io.Writer object. The password hash is never included.
+func toJSON(u *store.User, w io.Writer) error {
+	return json.NewEncoder(w).Encode(public(u))
+}
+
+// fromJSON tries to decode the payload into u from the io.Reader object.
+func fromJSON(u *store.User, r io.Reader) error {
+	return json.NewDecoder(r).Decode(u)
+}
+
+// hashPassword replaces u.Password with its bcrypt hash. It is a no-op if
+// u.Password is empty.
+func hashPassword(u *store.User) error {
+	if u.Password == "" {
+		return nil
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(u.Password), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+
+	u.Password = string(hash)
+	return nil
+}
+
+// checkPassword reports whether candidate matches u's stored password hash.
+func checkPassword(u *store.User, candidate string) bool {
+	return bcrypt.CompareHashAndPassword([]byte(u.Password), []byte(candidate)) == nil
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+
+	"github.com/imariom/go-users-api/store"
+)
+
+// validate is the package-wide validator instance; validator.Validate is
+// safe for concurrent use once configured, so a single instance is shared
+// across requests.
+var validate = validator.New(validator.WithRequiredStructEnabled())
+
+// validateUser checks u against the validate tags on store.User, returning
+// one fieldError per violation, or nil if u is valid.
+func validateUser(u *store.User) []fieldError {
+	err := validate.Struct(u)
+	if err == nil {
+		return nil
+	}
+
+	var errs []fieldError
+	for _, fe := range err.(validator.ValidationErrors) {
+		errs = append(errs, fieldError{
+			Field:  strings.ToLower(fe.Field()),
+			Reason: validationReason(fe),
+		})
+	}
+	return errs
+}
+
+// validationReason turns a validator.FieldError into a human-readable
+// explanation suitable for an API response.
+func validationReason(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required":
+		return "is required"
+	case "min":
+		return fmt.Sprintf("must be at least %s characters long", fe.Param())
+	case "email":
+		return "must be a valid email address"
+	default:
+		return "is invalid"
+	}
+}
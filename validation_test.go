@@ -0,0 +1,50 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/imariom/go-users-api/store"
+)
+
+func TestValidateUser_Valid(t *testing.T) {
+	u := &store.User{Username: "alice", Password: "hunter22", Email: "alice@example.com"}
+	if errs := validateUser(u); errs != nil {
+		t.Fatalf("validateUser() = %+v, want nil", errs)
+	}
+}
+
+func TestValidateUser_ReportsFieldErrors(t *testing.T) {
+	u := &store.User{Username: "ab", Password: "short", Email: "not-an-email"}
+
+	errs := validateUser(u)
+	if errs == nil {
+		t.Fatal("validateUser() = nil, want field errors")
+	}
+
+	byField := map[string]string{}
+	for _, e := range errs {
+		byField[e.Field] = e.Reason
+	}
+
+	if _, ok := byField["username"]; !ok {
+		t.Errorf("validateUser() missing error for username (min length)")
+	}
+	if _, ok := byField["password"]; !ok {
+		t.Errorf("validateUser() missing error for password (min length)")
+	}
+	if _, ok := byField["email"]; !ok {
+		t.Errorf("validateUser() missing error for email (format)")
+	}
+}
+
+func TestValidateUser_ReportsMissingRequiredFields(t *testing.T) {
+	errs := validateUser(&store.User{})
+	if len(errs) != 3 {
+		t.Fatalf("validateUser(&store.User{}) = %+v, want 3 field errors", errs)
+	}
+	for _, e := range errs {
+		if e.Reason != "is required" {
+			t.Errorf("field %q reason = %q, want %q", e.Field, e.Reason, "is required")
+		}
+	}
+}